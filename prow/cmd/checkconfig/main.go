@@ -18,9 +18,14 @@ limitations under the License.
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
 	"strings"
 
 	"github.com/sirupsen/logrus"
@@ -38,6 +43,7 @@ import (
 	"k8s.io/test-infra/prow/plugins/trigger"
 	"k8s.io/test-infra/prow/plugins/verify-owners"
 	"k8s.io/test-infra/prow/plugins/wip"
+	"sigs.k8s.io/yaml"
 
 	"k8s.io/test-infra/prow/config"
 	_ "k8s.io/test-infra/prow/hook"
@@ -46,21 +52,108 @@ import (
 	"k8s.io/test-infra/prow/plugins/lgtm"
 )
 
+const (
+	outputFormatText  = "text"
+	outputFormatJSON  = "json"
+	outputFormatSarif = "sarif"
+)
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
 type options struct {
-	configPath    string
-	jobConfigPath string
-	pluginConfig  string
+	configPath           string
+	jobConfigPath        string
+	pluginConfig         string
+	enforcementConfig    string
+	allowedPluginsConfig string
+	outputFormat         string
 
 	warnings flagutil.Strings
 	strict   bool
 }
 
-func reportWarning(strict bool, errs errorutil.Aggregate) {
-	for _, item := range errs.Strings() {
-		logrus.Warn(item)
+// Finding is a single structured validation result. It is logged as text by
+// default, or emitted as JSON or SARIF instead when selected via
+// --output-format, which lets checkconfig annotate PRs on code-review
+// surfaces instead of just dumping log lines.
+type Finding struct {
+	Warning  string `json:"warning"`
+	Severity string `json:"severity"`
+	Org      string `json:"org,omitempty"`
+	Repo     string `json:"repo,omitempty"`
+	Job      string `json:"job,omitempty"`
+	Message  string `json:"message"`
+	RuleID   string `json:"ruleId"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+}
+
+// newFinding builds a Finding for a warning that was found while looking at
+// a single "org/repo" string (or "" if the warning has no specific scope),
+// splitting out the org.
+func newFinding(warning, repo, message string) Finding {
+	f := Finding{Warning: warning, RuleID: warning, Repo: repo, Message: message}
+	if parts := strings.SplitN(repo, "/", 2); len(parts) == 2 {
+		f.Org = parts[0]
+	} else {
+		f.Org = repo
+	}
+	return f
+}
+
+// newJobFinding is like newFinding but additionally records which job the
+// finding is about, and the job config file it came from, when known.
+func newJobFinding(warning, repo, job, sourcePath, message string) Finding {
+	f := newFinding(warning, repo, message)
+	f.Job = job
+	f.File = sourcePath
+	return f
+}
+
+// report logs every finding in text mode or, in json/sarif mode, emits them
+// as a structured document on stdout instead of logging them. The exit code
+// always reflects strictness, regardless of output format.
+func report(outputFormat string, enforcement *enforcementTable, findings []Finding) {
+	strict := false
+	for i := range findings {
+		if enforcement.isStrict(findings[i].Warning, findings[i].Org, findings[i].Repo) {
+			findings[i].Severity = severityError
+			strict = true
+		} else {
+			findings[i].Severity = severityWarning
+		}
+	}
+
+	switch outputFormat {
+	case outputFormatJSON:
+		// A nil findings slice encodes as the JSON literal `null`, not `[]`;
+		// always emit an array so consumers can unmarshal and range over it
+		// unconditionally, even on a clean config.
+		jsonFindings := findings
+		if jsonFindings == nil {
+			jsonFindings = []Finding{}
+		}
+		if err := json.NewEncoder(os.Stdout).Encode(jsonFindings); err != nil {
+			logrus.WithError(err).Fatal("Error encoding findings as JSON.")
+		}
+	case outputFormatSarif:
+		if err := writeSarif(os.Stdout, findings); err != nil {
+			logrus.WithError(err).Fatal("Error encoding findings as SARIF.")
+		}
+	default:
+		for _, finding := range findings {
+			logrus.Warn(finding.Message)
+		}
 	}
+
 	if strict {
-		logrus.Fatal("Strict is set and there were warnings")
+		if outputFormat == outputFormatText {
+			logrus.Fatal("Strict is set and there were warnings")
+		}
+		os.Exit(1)
 	}
 }
 
@@ -74,10 +167,12 @@ func (o *options) warningEnabled(warning string) bool {
 }
 
 const (
-	mismatchedTideWarning   = "mismatched-tide"
-	nonDecoratedJobsWarning = "non-decorated-jobs"
-	jobNameLengthWarning    = "long-job-names"
-	needsOkToTestWarning    = "needs-ok-to-test"
+	mismatchedTideWarning     = "mismatched-tide"
+	nonDecoratedJobsWarning   = "non-decorated-jobs"
+	jobNameLengthWarning      = "long-job-names"
+	needsOkToTestWarning      = "needs-ok-to-test"
+	disallowedPluginsWarning  = "disallowed-plugins"
+	unmatchedTideQueryWarning = "unmatched-tide-query"
 )
 
 var allWarnings = []string{
@@ -85,6 +180,8 @@ var allWarnings = []string{
 	nonDecoratedJobsWarning,
 	jobNameLengthWarning,
 	needsOkToTestWarning,
+	disallowedPluginsWarning,
+	unmatchedTideQueryWarning,
 }
 
 func (o *options) Validate() error {
@@ -106,6 +203,11 @@ func (o *options) Validate() error {
 			return fmt.Errorf("no such warning %q, valid warnings: %v", warning, allWarnings)
 		}
 	}
+	switch o.outputFormat {
+	case outputFormatText, outputFormatJSON, outputFormatSarif:
+	default:
+		return fmt.Errorf("no such output format %q, valid formats: %q, %q, %q", o.outputFormat, outputFormatText, outputFormatJSON, outputFormatSarif)
+	}
 	return nil
 }
 
@@ -114,6 +216,9 @@ func gatherOptions() options {
 	flag.StringVar(&o.configPath, "config-path", "", "Path to config.yaml.")
 	flag.StringVar(&o.jobConfigPath, "job-config-path", "", "Path to prow job configs.")
 	flag.StringVar(&o.pluginConfig, "plugin-config", "", "Path to plugin config file.")
+	flag.StringVar(&o.enforcementConfig, "enforcement-config", "", "Path to a file scoping which warnings are errors for which orgs/repos, overriding --strict.")
+	flag.StringVar(&o.allowedPluginsConfig, "allowed-plugins-config", "", "Path to a file listing, per org/repo, regular expressions for the plugins that may be enabled there. Used by the disallowed-plugins warning.")
+	flag.StringVar(&o.outputFormat, "output-format", outputFormatText, "Format for findings: text, json, or sarif.")
 	flag.Var(&o.warnings, "warnings", "Comma-delimited list of warnings to validate.")
 	flag.BoolVar(&o.strict, "strict", false, "If set, consider all warnings as errors.")
 	flag.Parse()
@@ -135,6 +240,19 @@ func main() {
 		logrusutil.NewDefaultFieldsFormatter(&logrus.TextFormatter{}, logrus.Fields{"component": "checkconfig"}),
 	)
 
+	var policies []EnforcementPolicy
+	if o.enforcementConfig != "" {
+		var err error
+		policies, err = loadEnforcementPolicies(o.enforcementConfig)
+		if err != nil {
+			logrus.WithError(err).Fatal("Error loading enforcement config.")
+		}
+	}
+	enforcement, err := newEnforcementTable(o.strict, policies)
+	if err != nil {
+		logrus.WithError(err).Fatal("Error parsing enforcement config.")
+	}
+
 	configAgent := config.Agent{}
 	if err := configAgent.Start(o.configPath, o.jobConfigPath); err != nil {
 		logrus.WithError(err).Fatal("Error loading Prow config.")
@@ -147,53 +265,405 @@ func main() {
 	}
 	pcfg := pluginAgent.Config()
 
+	var allowedPlugins []pluginAllowScope
+	if o.allowedPluginsConfig != "" {
+		policy, err := loadAllowedPluginsConfig(o.allowedPluginsConfig)
+		if err != nil {
+			logrus.WithError(err).Fatal("Error loading allowed plugins config.")
+		}
+		allowedPlugins, err = compileAllowedPluginsPolicy(policy)
+		if err != nil {
+			logrus.WithError(err).Fatal("Error parsing allowed plugins config.")
+		}
+	}
+
 	// the following checks are useful in finding user errors but their
 	// presence won't lead to strictly incorrect behavior, so we can
 	// detect them here but don't necessarily want to stop config re-load
 	// in all components on their failure.
-	var errs []error
+	var findings []Finding
 	if o.warningEnabled(mismatchedTideWarning) {
-		if err := validateTideRequirements(cfg, pcfg); err != nil {
-			errs = append(errs, err)
-		}
+		findings = append(findings, validateTideRequirements(cfg, pcfg)...)
 	}
 	if o.warningEnabled(nonDecoratedJobsWarning) {
-		if err := validateDecoratedJobs(cfg); err != nil {
-			errs = append(errs, err)
-		}
+		findings = append(findings, validateDecoratedJobs(cfg)...)
 	}
 	if o.warningEnabled(jobNameLengthWarning) {
-		if err := validateJobRequirements(cfg.JobConfig); err != nil {
-			errs = append(errs, err)
-		}
+		findings = append(findings, validateJobRequirements(cfg.JobConfig)...)
 	}
 	if o.warningEnabled(needsOkToTestWarning) {
-		if err := validateNeedsOkToTestLabel(cfg); err != nil {
-			errs = append(errs, err)
+		findings = append(findings, validateNeedsOkToTestLabel(cfg)...)
+	}
+	if o.warningEnabled(disallowedPluginsWarning) {
+		findings = append(findings, validateAllowedPlugins(pcfg, allowedPlugins)...)
+	}
+	if o.warningEnabled(unmatchedTideQueryWarning) {
+		findings = append(findings, validateUnmatchedTideQueries(cfg, pcfg)...)
+	}
+	report(o.outputFormat, enforcement, findings)
+}
+
+// sarifLog is a minimal SARIF 2.1.0 document containing exactly what
+// checkconfig needs to annotate findings. See
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+func writeSarif(w io.Writer, findings []Finding) error {
+	log := sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Runs: []sarifRun{
+			{
+				Tool:    sarifTool{Driver: sarifDriver{Name: "checkconfig", Rules: sarifRules()}},
+				Results: sarifResults(findings),
+			},
+		},
+	}
+	return json.NewEncoder(w).Encode(log)
+}
+
+func sarifRules() []sarifRule {
+	rules := make([]sarifRule, 0, len(allWarnings))
+	for _, warning := range allWarnings {
+		rules = append(rules, sarifRule{ID: warning, Name: warning})
+	}
+	return rules
+}
+
+func sarifResults(findings []Finding) []sarifResult {
+	results := make([]sarifResult, 0, len(findings))
+	for _, finding := range findings {
+		level := severityWarning
+		if finding.Severity == severityError {
+			level = severityError
+		}
+		result := sarifResult{RuleID: finding.RuleID, Level: level, Message: sarifMessage{Text: finding.Message}}
+		if finding.File != "" {
+			var region *sarifRegion
+			if finding.Line > 0 {
+				region = &sarifRegion{StartLine: finding.Line}
+			}
+			result.Locations = []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: finding.File}, Region: region}}}
 		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// EnforcementPolicy pins a single warning to an action ("error" or "warn")
+// for one or more scopes. This lets large monorepos ratchet up --strict
+// repo-by-repo instead of forcing one global cutover: a scope's action
+// overrides the global --strict default for any finding of that warning
+// in that org/repo.
+type EnforcementPolicy struct {
+	Warning string             `json:"warning"`
+	Action  string             `json:"action"`
+	Scopes  []EnforcementScope `json:"scopes"`
+}
+
+// EnforcementScope is an org, optionally narrowed to specific repos within it.
+// An empty Repos list means the whole org.
+type EnforcementScope struct {
+	Org   string   `json:"org"`
+	Repos []string `json:"repos,omitempty"`
+}
+
+const (
+	errorAction = "error"
+	warnAction  = "warn"
+)
+
+func loadEnforcementPolicies(path string) ([]EnforcementPolicy, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read enforcement config: %v", err)
 	}
-	if len(errs) > 0 {
-		reportWarning(o.strict, errorutil.NewAggregate(errs...))
+	var policies []EnforcementPolicy
+	if err := yaml.Unmarshal(raw, &policies); err != nil {
+		return nil, fmt.Errorf("could not unmarshal enforcement config: %v", err)
 	}
+	return policies, nil
 }
 
-func validateJobRequirements(c config.JobConfig) error {
-	var validationErrs []error
+// enforcementTable decides, for a given warning and org/repo, whether a
+// finding should be fatal. Scopes configured via --enforcement-config take
+// precedence over the global --strict default.
+type enforcementTable struct {
+	strict      bool
+	errorScopes map[string]*orgRepoConfig
+	warnScopes  map[string]*orgRepoConfig
+}
+
+// enforcementKey identifies the (warning, action) pair multiple
+// EnforcementPolicy entries can share, e.g. one entry per team scoping the
+// same warning to the same action.
+type enforcementKey struct {
+	warning string
+	action  string
+}
+
+func newEnforcementTable(strict bool, policies []EnforcementPolicy) (*enforcementTable, error) {
+	t := &enforcementTable{
+		strict:      strict,
+		errorScopes: map[string]*orgRepoConfig{},
+		warnScopes:  map[string]*orgRepoConfig{},
+	}
+	// Accumulate every policy's scopes by (warning, action) before building
+	// the orgRepoConfigs, so that two policies sharing a warning+action
+	// (e.g. one entry per team) union their scopes instead of one
+	// overwriting the other.
+	orgExceptionsByKey := map[enforcementKey]map[string]sets.String{}
+	reposByKey := map[enforcementKey]sets.String{}
+	var keysInOrder []enforcementKey
+	seenKeys := map[enforcementKey]bool{}
+	for _, policy := range policies {
+		switch policy.Action {
+		case errorAction, warnAction:
+		default:
+			return nil, fmt.Errorf("enforcement config for warning %q has unknown action %q, must be %q or %q", policy.Warning, policy.Action, errorAction, warnAction)
+		}
+		key := enforcementKey{warning: policy.Warning, action: policy.Action}
+		if !seenKeys[key] {
+			seenKeys[key] = true
+			keysInOrder = append(keysInOrder, key)
+			orgExceptionsByKey[key] = map[string]sets.String{}
+			reposByKey[key] = sets.NewString()
+		}
+		for _, scope := range policy.Scopes {
+			if len(scope.Repos) == 0 {
+				orgExceptionsByKey[key][scope.Org] = sets.NewString()
+				continue
+			}
+			reposByKey[key].Insert(scope.Repos...)
+		}
+	}
+
+	for _, key := range keysInOrder {
+		scopeConfig := newOrgRepoConfig(orgExceptionsByKey[key], reposByKey[key])
+		switch key.action {
+		case errorAction:
+			t.errorScopes[key.warning] = scopeConfig
+		case warnAction:
+			t.warnScopes[key.warning] = scopeConfig
+		}
+	}
+	return t, nil
+}
+
+// isStrict returns whether a finding for warning in org/repo should be fatal.
+func (t *enforcementTable) isStrict(warning, org, repo string) bool {
+	if scopeConfig, ok := t.errorScopes[warning]; ok && scopeConfig.has(org, repo) {
+		return true
+	}
+	if scopeConfig, ok := t.warnScopes[warning]; ok && scopeConfig.has(org, repo) {
+		return false
+	}
+	return t.strict
+}
+
+// pluginAllowScope is a single entry from --allowed-plugins-config: the
+// scope of orgs/repos it applies to, plus the plugin name patterns
+// permitted for that scope. A key ending in "/*" denotes a whole org and is
+// matched with the same orgRepoConfig machinery that tide/plugin matching
+// already uses; a key that is a literal org or org/repo is matched the same
+// way. Any other key (e.g. "kubernetes-sigs/.+") is compiled as a regular
+// expression and matched against the full "org" or "org/repo" string, so
+// that a regex scope key is not treated as a literal repo name. A regex key
+// that matches no org or repo owns nothing and is not reported.
+type pluginAllowScope struct {
+	name         string
+	scope        *orgRepoConfig
+	scopePattern *regexp.Regexp
+	allowed      []*regexp.Regexp
+}
+
+// literalOrgOrRepo matches a plain org ("kubernetes") or org/repo
+// ("kubernetes/kubernetes") string containing no regex metacharacters.
+var literalOrgOrRepo = regexp.MustCompile(`^[\w.-]+(/[\w.-]+)?$`)
+
+func loadAllowedPluginsConfig(path string) (map[string][]string, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read allowed plugins config: %v", err)
+	}
+	var policy map[string][]string
+	if err := yaml.Unmarshal(raw, &policy); err != nil {
+		return nil, fmt.Errorf("could not unmarshal allowed plugins config: %v", err)
+	}
+	return policy, nil
+}
+
+func compileAllowedPluginsPolicy(policy map[string][]string) ([]pluginAllowScope, error) {
+	var scopes []pluginAllowScope
+	for key, allowed := range policy {
+		var scope *orgRepoConfig
+		var scopePattern *regexp.Regexp
+		switch {
+		case strings.HasSuffix(key, "/*"):
+			org := strings.TrimSuffix(key, "/*")
+			scope = newOrgRepoConfig(map[string]sets.String{org: sets.NewString()}, sets.NewString())
+		case literalOrgOrRepo.MatchString(key) && !strings.Contains(key, "/"):
+			// A bare org, e.g. "kubernetes", is whole-org shorthand just
+			// like "kubernetes/*".
+			scope = newOrgRepoConfig(map[string]sets.String{key: sets.NewString()}, sets.NewString())
+		case literalOrgOrRepo.MatchString(key):
+			scope = newOrgRepoConfig(map[string]sets.String{}, sets.NewString(key))
+		default:
+			re, err := regexp.Compile("^" + key + "$")
+			if err != nil {
+				return nil, fmt.Errorf("scope %q in --allowed-plugins-config is neither a literal org/repo nor a valid regular expression: %v", key, err)
+			}
+			scopePattern = re
+		}
+
+		allowedPatterns := make([]*regexp.Regexp, 0, len(allowed))
+		for _, pattern := range allowed {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid plugin pattern %q for scope %q in --allowed-plugins-config: %v", pattern, key, err)
+			}
+			allowedPatterns = append(allowedPatterns, re)
+		}
+		scopes = append(scopes, pluginAllowScope{name: key, scope: scope, scopePattern: scopePattern, allowed: allowedPatterns})
+	}
+	return scopes, nil
+}
+
+// validateAllowedPlugins flags any plugin enabled in pcfg.Plugins that does
+// not match an allow pattern for its owning scope in --allowed-plugins-config.
+func validateAllowedPlugins(pcfg *plugins.Configuration, scopes []pluginAllowScope) []Finding {
+	var findings []Finding
+	for orgOrRepo, enabledPlugins := range pcfg.Plugins {
+		org := orgOrRepo
+		if parts := strings.SplitN(orgOrRepo, "/", 2); len(parts) == 2 {
+			org = parts[0]
+		}
+		owningScopes := scopesOwning(scopes, org, orgOrRepo)
+		for _, plugin := range enabledPlugins {
+			if pluginAllowedByScopes(owningScopes, plugin) {
+				continue
+			}
+			findings = append(findings, newFinding(disallowedPluginsWarning, orgOrRepo, fmt.Sprintf("%s enables plugin %q, which is not permitted by any rule in --allowed-plugins-config (closest rule(s): %s)", orgOrRepo, plugin, describeScopes(owningScopes))))
+		}
+	}
+	return findings
+}
+
+func scopesOwning(scopes []pluginAllowScope, org, repo string) []pluginAllowScope {
+	var owning []pluginAllowScope
+	for _, s := range scopes {
+		if s.scopePattern != nil {
+			if s.scopePattern.MatchString(repo) || s.scopePattern.MatchString(org) {
+				owning = append(owning, s)
+			}
+			continue
+		}
+		if s.scope.has(org, repo) {
+			owning = append(owning, s)
+		}
+	}
+	return owning
+}
+
+func pluginAllowedByScopes(scopes []pluginAllowScope, plugin string) bool {
+	for _, s := range scopes {
+		for _, re := range s.allowed {
+			if re.MatchString(plugin) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func describeScopes(scopes []pluginAllowScope) string {
+	if len(scopes) == 0 {
+		return "none configured"
+	}
+	names := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		names = append(names, s.name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func validateJobRequirements(c config.JobConfig) []Finding {
+	var findings []Finding
 	for repo, jobs := range c.Presubmits {
 		for _, job := range jobs {
-			validationErrs = append(validationErrs, validatePresubmitJob(repo, job))
+			if err := validatePresubmitJob(repo, job); err != nil {
+				findings = append(findings, newJobFinding(jobNameLengthWarning, repo, job.Name, job.SourcePath, err.Error()))
+			}
 		}
 	}
 	for repo, jobs := range c.Postsubmits {
 		for _, job := range jobs {
-			validationErrs = append(validationErrs, validatePostsubmitJob(repo, job))
+			if err := validatePostsubmitJob(repo, job); err != nil {
+				findings = append(findings, newJobFinding(jobNameLengthWarning, repo, job.Name, job.SourcePath, err.Error()))
+			}
 		}
 	}
 	for _, job := range c.Periodics {
-		validationErrs = append(validationErrs, validatePeriodicJob(job))
+		if err := validatePeriodicJob(job); err != nil {
+			f := newFinding(jobNameLengthWarning, "", err.Error())
+			f.Job = job.Name
+			f.File = job.SourcePath
+			findings = append(findings, f)
+		}
 	}
 
-	return errorutil.NewAggregate(validationErrs...)
+	return findings
 }
 
 func validatePresubmitJob(repo string, job config.Presubmit) error {
@@ -223,7 +693,7 @@ func validatePeriodicJob(job config.Periodic) error {
 	return errorutil.NewAggregate(validationErrs...)
 }
 
-func validateTideRequirements(cfg *config.Config, pcfg *plugins.Configuration) error {
+func validateTideRequirements(cfg *config.Config, pcfg *plugins.Configuration) []Finding {
 	type matcher struct {
 		// matches determines if the tide query appropriately honors the
 		// label in question -- whether by requiring it or forbidding it
@@ -283,52 +753,305 @@ func validateTideRequirements(cfg *config.Config, pcfg *plugins.Configuration) e
 	overallTideConfig := newOrgRepoConfig(cfg.Tide.Queries.OrgExceptionsAndRepos())
 
 	// Now actually execute the checks we just configured.
-	var validationErrs []error
+	var findings []Finding
 	for _, pluginConfig := range configs {
-		err := ensureValidConfiguration(
+		findings = append(findings, ensureValidConfiguration(
 			pluginConfig.plugin,
 			pluginConfig.label,
 			pluginConfig.matcher.verb,
 			pluginConfig.config,
 			overallTideConfig,
 			enabledOrgReposForPlugin(pcfg, pluginConfig.plugin),
-		)
-		validationErrs = append(validationErrs, err)
+		)...)
 	}
 
-	return errorutil.NewAggregate(validationErrs...)
+	return findings
 }
 
+// tideRelevantPlugins are the plugins validateTideRequirements checks for
+// matching tide label requirements; validateUnmatchedTideQueries reuses the
+// same list to decide whether a tide query target has any eligible plugin
+// enabled at all.
+var tideRelevantPlugins = []string{
+	lgtm.PluginName,
+	approve.PluginName,
+	hold.PluginName,
+	wip.PluginName,
+	verifyowners.PluginName,
+	releasenote.PluginName,
+	cherrypickunapproved.PluginName,
+	blockade.PluginName,
+	needsrebase.PluginName,
+}
+
+// orgOf returns the org portion of an "org/repo" string, or repo itself if
+// it does not contain a "/".
+func orgOf(repo string) string {
+	if parts := strings.SplitN(repo, "/", 2); len(parts) == 2 {
+		return parts[0]
+	}
+	return repo
+}
+
+// validateUnmatchedTideQueries flags tide queries that target an org, repo,
+// or repo pattern for which no repository actually exists in the loaded
+// config, or for which no tide-relevant plugin is enabled anywhere. This
+// catches a typo in a tide query that would otherwise silently match no
+// repos, leaving checkconfig with nothing to complain about.
+func validateUnmatchedTideQueries(cfg *config.Config, pcfg *plugins.Configuration) []Finding {
+	var findings []Finding
+	for i, query := range cfg.Tide.Queries {
+		queryConfig := newOrgRepoConfig(config.TideQueries{query}.OrgExceptionsAndRepos())
+		for _, item := range queryConfig.items() {
+			if !queryTargetHasRepo(cfg, item) {
+				findings = append(findings, Finding{
+					Warning: unmatchedTideQueryWarning,
+					RuleID:  unmatchedTideQueryWarning,
+					Org:     item.org,
+					Repo:    item.repo,
+					Message: fmt.Sprintf("tide query at position %d targets %s, which does not match any repository in the loaded config", i, item),
+				})
+				continue
+			}
+			if !queryTargetHasEnabledPlugin(cfg, pcfg, item) {
+				findings = append(findings, Finding{
+					Warning: unmatchedTideQueryWarning,
+					RuleID:  unmatchedTideQueryWarning,
+					Org:     item.org,
+					Repo:    item.repo,
+					Message: fmt.Sprintf("tide query at position %d targets %s, which has no tide-relevant plugin enabled anywhere under it", i, item),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// queryTargetHasRepo reports whether item (an org, repo, or repo pattern)
+// matches at least one repository present in cfg.AllRepos.
+func queryTargetHasRepo(cfg *config.Config, item scopeItem) bool {
+	switch {
+	case item.pattern != "":
+		for repo := range cfg.AllRepos {
+			if item.re.MatchString(repo) {
+				return true
+			}
+		}
+		return false
+	case item.repo != "":
+		return cfg.AllRepos.Has(item.repo)
+	default:
+		prefix := item.org + "/"
+		for repo := range cfg.AllRepos {
+			if strings.HasPrefix(repo, prefix) && !item.excepts.Has(repo) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// queryTargetHasEnabledPlugin reports whether any tide-relevant plugin is
+// enabled for at least one repo matching item.
+func queryTargetHasEnabledPlugin(cfg *config.Config, pcfg *plugins.Configuration, item scopeItem) bool {
+	for _, plugin := range tideRelevantPlugins {
+		enabled := enabledOrgReposForPlugin(pcfg, plugin)
+		switch {
+		case item.pattern != "":
+			for repo := range cfg.AllRepos {
+				if item.re.MatchString(repo) && enabled.has(orgOf(repo), repo) {
+					return true
+				}
+			}
+		case item.repo != "":
+			if enabled.has(orgOf(item.repo), item.repo) {
+				return true
+			}
+		default:
+			prefix := item.org + "/"
+			for repo := range cfg.AllRepos {
+				if strings.HasPrefix(repo, prefix) && !item.excepts.Has(repo) && enabled.has(item.org, repo) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// newOrgRepoConfig builds an orgRepoConfig from literal org exceptions and
+// repos. Any repo entry that is actually a glob pattern (e.g.
+// "kubernetes-sigs/cluster-api-provider-*") is split out and compiled to a
+// regexp instead of being treated as a literal repo.
 func newOrgRepoConfig(orgExceptions map[string]sets.String, repos sets.String) *orgRepoConfig {
-	return &orgRepoConfig{
+	c := &orgRepoConfig{
 		orgExceptions: orgExceptions,
-		repos:         repos,
+		repos:         sets.NewString(),
+	}
+	for _, repo := range repos.UnsortedList() {
+		if re, raw, ok := compileRepoPattern(repo); ok {
+			c.patterns = append(c.patterns, re)
+			c.patternSources = append(c.patternSources, raw)
+			continue
+		}
+		c.repos.Insert(repo)
+	}
+	return c
+}
+
+// compileRepoPattern compiles repo as a glob pattern if it contains glob
+// metacharacters ('*' or '?'), translating them to their regexp
+// equivalents and escaping everything else.
+func compileRepoPattern(repo string) (*regexp.Regexp, string, bool) {
+	if !strings.ContainsAny(repo, "*?") {
+		return nil, "", false
+	}
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range repo {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
 	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String()), repo, true
 }
 
 type orgRepoConfig struct {
 	orgExceptions map[string]sets.String
 	repos         sets.String
+	// patterns holds repo-pattern entries (e.g. "org/repo-*") compiled from
+	// repos passed to newOrgRepoConfig; patternSources holds the raw text
+	// of each, in the same order, for display purposes.
+	patterns       []*regexp.Regexp
+	patternSources []string
+	// orgExceptionPatterns holds, per org with an orgExceptions entry,
+	// additional repo patterns that are excepted from that org (on top of
+	// the literal excepts in orgExceptions). difference populates this so
+	// that a repo pattern on one side is honored against a whole-org entry
+	// on the other side, not just against literal repos.
+	orgExceptionPatterns map[string][]*regexp.Regexp
 }
 
-func (c *orgRepoConfig) items() []string {
-	items := make([]string, 0, len(c.orgExceptions)+len(c.repos))
-	for org, excepts := range c.orgExceptions {
-		item := fmt.Sprintf("org: %s", org)
-		if excepts.Len() > 0 {
-			item = fmt.Sprintf("%s without repo(s) %s", item, strings.Join(excepts.List(), ", "))
-			for _, repo := range excepts.List() {
-				item = fmt.Sprintf("%s '%s'", item, repo)
-			}
+// scopeItem is a single entry produced by orgRepoConfig.items, carrying the
+// org/repo (or repo pattern) it refers to alongside the text used to
+// describe it in an error message.
+type scopeItem struct {
+	org     string
+	repo    string
+	pattern string
+	re      *regexp.Regexp
+	excepts sets.String
+}
+
+func (s scopeItem) String() string {
+	if s.pattern != "" {
+		return fmt.Sprintf("repo pattern: %s", s.pattern)
+	}
+	if s.repo != "" {
+		return fmt.Sprintf("repo: %s", s.repo)
+	}
+	item := fmt.Sprintf("org: %s", s.org)
+	if s.excepts.Len() > 0 {
+		item = fmt.Sprintf("%s without repo(s) %s", item, strings.Join(s.excepts.List(), ", "))
+		for _, repo := range s.excepts.List() {
+			item = fmt.Sprintf("%s '%s'", item, repo)
 		}
-		items = append(items, item)
+	}
+	return item
+}
+
+func (c *orgRepoConfig) items() []scopeItem {
+	items := make([]scopeItem, 0, len(c.orgExceptions)+len(c.repos)+len(c.patterns))
+	for org, excepts := range c.orgExceptions {
+		items = append(items, scopeItem{org: org, excepts: excepts})
 	}
 	for _, repo := range c.repos.List() {
-		items = append(items, fmt.Sprintf("repo: %s", repo))
+		items = append(items, scopeItem{org: orgOf(repo), repo: repo})
+	}
+	for i, re := range c.patterns {
+		items = append(items, scopeItem{org: orgOf(c.patternSources[i]), pattern: c.patternSources[i], re: re})
 	}
 	return items
 }
 
+// has determines whether org/repo is contained in this config, including
+// via a repo pattern that matches repo.
+func (c *orgRepoConfig) has(org, repo string) bool {
+	if repo != "" {
+		if c.repos.Has(repo) {
+			return true
+		}
+		if c.matchesPattern(repo) {
+			return true
+		}
+	}
+	if excepts, ok := c.orgExceptions[org]; ok {
+		if repo == "" {
+			return true
+		}
+		if excepts.Has(repo) {
+			return false
+		}
+		for _, re := range c.orgExceptionPatterns[org] {
+			if re.MatchString(repo) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// matchesPattern reports whether repo matches any repo pattern in c.
+func (c *orgRepoConfig) matchesPattern(repo string) bool {
+	for _, re := range c.patterns {
+		if re.MatchString(repo) {
+			return true
+		}
+	}
+	return false
+}
+
+// reposMatchingPatterns returns the subset of repos that match at least one
+// of patterns. A repo covered by a pattern is treated as contained in a
+// config for the purposes of difference/intersection, even though the
+// pattern and the repo are never literally equal.
+func reposMatchingPatterns(repos sets.String, patterns []*regexp.Regexp) sets.String {
+	matching := sets.NewString()
+	for _, repo := range repos.UnsortedList() {
+		for _, re := range patterns {
+			if re.MatchString(repo) {
+				matching.Insert(repo)
+				break
+			}
+		}
+	}
+	return matching
+}
+
+// patternsForOrg returns the subset of patterns (with their raw sources)
+// that are scoped to org, i.e. whose raw text starts with "org/". It lets
+// an org-exceptions entry for org resolve against only the patterns that
+// could plausibly apply to it.
+func patternsForOrg(patterns []*regexp.Regexp, sources []string, org string) ([]*regexp.Regexp, []string) {
+	prefix := org + "/"
+	var matched []*regexp.Regexp
+	var matchedSources []string
+	for i, raw := range sources {
+		if strings.HasPrefix(raw, prefix) {
+			matched = append(matched, patterns[i])
+			matchedSources = append(matchedSources, raw)
+		}
+	}
+	return matched, matchedSources
+}
+
 // difference returns a new orgRepoConfig that represents the set difference of
 // the repos specified by the receiver and the parameter orgRepoConfigs.
 func (c *orgRepoConfig) difference(c2 *orgRepoConfig) *orgRepoConfig {
@@ -348,10 +1071,22 @@ func (c *orgRepoConfig) difference(c2 *orgRepoConfig) *orgRepoConfig {
 				}
 			}
 			res.orgExceptions[org] = excepts
+			// A repo in org matched by one of c2's patterns is also
+			// already contained in c2, so it must be excepted too -- not
+			// just the literal repos c2 spells out.
+			if orgPatterns, _ := patternsForOrg(c2.patterns, c2.patternSources, org); len(orgPatterns) > 0 {
+				if res.orgExceptionPatterns == nil {
+					res.orgExceptionPatterns = map[string][]*regexp.Regexp{}
+				}
+				res.orgExceptionPatterns[org] = orgPatterns
+			}
 		}
 	}
 
 	res.repos = res.repos.Difference(c2.repos)
+	// A repo covered by a pattern on c2 is already contained in c2, so it
+	// does not belong in the difference either.
+	res.repos = res.repos.Difference(reposMatchingPatterns(res.repos, c2.patterns))
 
 	for _, repo := range res.repos.UnsortedList() {
 		if parts := strings.SplitN(repo, "/", 2); len(parts) == 2 {
@@ -360,6 +1095,19 @@ func (c *orgRepoConfig) difference(c2 *orgRepoConfig) *orgRepoConfig {
 			}
 		}
 	}
+
+	// Carry over the receiver's own pattern items, dropping only the ones
+	// c2 also declares (the same literal pattern on both sides cancels out,
+	// same as an equal literal repo would). A pattern that c2 doesn't
+	// declare can't be resolved against arbitrary future repos, so it must
+	// survive the difference rather than being silently dropped.
+	c2Patterns := sets.NewString(c2.patternSources...)
+	for i, raw := range c.patternSources {
+		if !c2Patterns.Has(raw) {
+			res.patterns = append(res.patterns, c.patterns[i])
+			res.patternSources = append(res.patternSources, raw)
+		}
+	}
 	return res
 }
 
@@ -381,6 +1129,13 @@ func (c *orgRepoConfig) intersection(c2 *orgRepoConfig) *orgRepoConfig {
 					res.repos.Insert(repo)
 				}
 			}
+			// Any repo under org that c2 covers via a pattern is also
+			// contained in c2, so the pattern itself belongs in the
+			// intersection alongside the literal repos above.
+			if orgPatterns, orgSources := patternsForOrg(c2.patterns, c2.patternSources, org); len(orgPatterns) > 0 {
+				res.patterns = append(res.patterns, orgPatterns...)
+				res.patternSources = append(res.patternSources, orgSources...)
+			}
 		}
 	}
 	for _, repo := range c.repos.UnsortedList() {
@@ -393,6 +1148,20 @@ func (c *orgRepoConfig) intersection(c2 *orgRepoConfig) *orgRepoConfig {
 			}
 		}
 	}
+	// A repo on either side that is covered by a pattern on the other side
+	// is contained in both, so it belongs in the intersection.
+	res.repos = res.repos.Union(reposMatchingPatterns(c.repos, c2.patterns))
+	res.repos = res.repos.Union(reposMatchingPatterns(c2.repos, c.patterns))
+
+	// A pattern declared on both sides is common to both, so it belongs in
+	// the intersection too.
+	c2Patterns := sets.NewString(c2.patternSources...)
+	for i, raw := range c.patternSources {
+		if c2Patterns.Has(raw) {
+			res.patterns = append(res.patterns, c.patterns[i])
+			res.patternSources = append(res.patternSources, raw)
+		}
+	}
 	return res
 }
 
@@ -412,64 +1181,78 @@ func enabledOrgReposForPlugin(c *plugins.Configuration, plugin string) *orgRepoC
 // Specifically:
 //   - every item in the tide subset must also be in the plugins subset
 //   - every item in the plugins subset that is in the tide superset must also be in the tide subset
+//
 // For example:
 //   - if org/repo is configured in tide to require lgtm, it must have the lgtm plugin enabled
 //   - if org/repo is configured in tide, the tide configuration must require the same set of
 //     plugins as are configured. If the repository has LGTM and approve enabled, the tide query
 //     must require both labels
-func ensureValidConfiguration(plugin, label, verb string, tideSubSet, tideSuperSet, pluginsSubSet *orgRepoConfig) error {
-	notEnabled := tideSubSet.difference(pluginsSubSet).items()
-	notRequired := pluginsSubSet.intersection(tideSuperSet).difference(tideSubSet).items()
-
-	var configErrors []error
-	if len(notEnabled) > 0 {
-		configErrors = append(configErrors, fmt.Errorf("the following orgs or repos %s the %s label for merging but do not enable the %s plugin: %v", verb, label, plugin, notEnabled))
+func ensureValidConfiguration(plugin, label, verb string, tideSubSet, tideSuperSet, pluginsSubSet *orgRepoConfig) []Finding {
+	var findings []Finding
+	for _, item := range tideSubSet.difference(pluginsSubSet).items() {
+		findings = append(findings, Finding{
+			Warning: mismatchedTideWarning,
+			RuleID:  mismatchedTideWarning,
+			Org:     item.org,
+			Repo:    item.repo,
+			Message: fmt.Sprintf("the following %s %s the %s label for merging but does not enable the %s plugin", item, verb, label, plugin),
+		})
 	}
-	if len(notRequired) > 0 {
-		configErrors = append(configErrors, fmt.Errorf("the following orgs or repos enable the %s plugin but do not %s the %s label for merging: %v", plugin, verb, label, notRequired))
+	for _, item := range pluginsSubSet.intersection(tideSuperSet).difference(tideSubSet).items() {
+		findings = append(findings, Finding{
+			Warning: mismatchedTideWarning,
+			RuleID:  mismatchedTideWarning,
+			Org:     item.org,
+			Repo:    item.repo,
+			Message: fmt.Sprintf("the following %s enables the %s plugin but does not %s the %s label for merging", item, plugin, verb, label),
+		})
 	}
 
-	return errorutil.NewAggregate(configErrors...)
+	return findings
 }
 
-func validateDecoratedJobs(cfg *config.Config) error {
-	var nonDecoratedJobs []string
-	for _, presubmit := range cfg.AllPresubmits([]string{}) {
-		if presubmit.Agent == string(v1.KubernetesAgent) && !presubmit.Decorate {
-			nonDecoratedJobs = append(nonDecoratedJobs, presubmit.Name)
+func validateDecoratedJobs(cfg *config.Config) []Finding {
+	var findings []Finding
+	for repo, jobs := range cfg.Presubmits {
+		for _, presubmit := range jobs {
+			if presubmit.Agent == string(v1.KubernetesAgent) && !presubmit.Decorate {
+				findings = append(findings, newJobFinding(nonDecoratedJobsWarning, repo, presubmit.Name, presubmit.SourcePath, fmt.Sprintf("the following job uses the kubernetes provider but does not use the pod utilities: %s", presubmit.Name)))
+			}
 		}
 	}
 
-	for _, postsubmit := range cfg.AllPostsubmits([]string{}) {
-		if postsubmit.Agent == string(v1.KubernetesAgent) && !postsubmit.Decorate {
-			nonDecoratedJobs = append(nonDecoratedJobs, postsubmit.Name)
+	for repo, jobs := range cfg.Postsubmits {
+		for _, postsubmit := range jobs {
+			if postsubmit.Agent == string(v1.KubernetesAgent) && !postsubmit.Decorate {
+				findings = append(findings, newJobFinding(nonDecoratedJobsWarning, repo, postsubmit.Name, postsubmit.SourcePath, fmt.Sprintf("the following job uses the kubernetes provider but does not use the pod utilities: %s", postsubmit.Name)))
+			}
 		}
 	}
 
-	for _, periodic := range cfg.AllPeriodics() {
+	for _, periodic := range cfg.Periodics {
 		if periodic.Agent == string(v1.KubernetesAgent) && !periodic.Decorate {
-			nonDecoratedJobs = append(nonDecoratedJobs, periodic.Name)
+			f := newFinding(nonDecoratedJobsWarning, "", fmt.Sprintf("the following job uses the kubernetes provider but does not use the pod utilities: %s", periodic.Name))
+			f.Job = periodic.Name
+			f.File = periodic.SourcePath
+			findings = append(findings, f)
 		}
 	}
 
-	if len(nonDecoratedJobs) > 0 {
-		return fmt.Errorf("the following jobs use the kubernetes provider but do not use the pod utilities: %v", nonDecoratedJobs)
-	}
-	return nil
+	return findings
 }
 
-func validateNeedsOkToTestLabel(cfg *config.Config) error {
-	var queryErrors []error
+func validateNeedsOkToTestLabel(cfg *config.Config) []Finding {
+	var findings []Finding
 	for i, query := range cfg.Tide.Queries {
 		for _, label := range query.Labels {
 			if label == lgtm.LGTMLabel {
 				for _, label := range query.MissingLabels {
 					if label == trigger.NeedsOkToTest {
-						queryErrors = append(queryErrors, fmt.Errorf("the tide query at position %d forbids the %q label and requires the %q label, which is not recommended; see https://github.com/kubernetes/test-infra/blob/master/prow/cmd/tide/maintainers.md#best-practices for more information", i, trigger.NeedsOkToTest, lgtm.LGTMLabel))
+						findings = append(findings, newFinding(needsOkToTestWarning, "", fmt.Sprintf("the tide query at position %d forbids the %q label and requires the %q label, which is not recommended; see https://github.com/kubernetes/test-infra/blob/master/prow/cmd/tide/maintainers.md#best-practices for more information", i, trigger.NeedsOkToTest, lgtm.LGTMLabel)))
 					}
 				}
 			}
 		}
 	}
-	return errorutil.NewAggregate(queryErrors...)
+	return findings
 }
@@ -0,0 +1,365 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/test-infra/prow/plugins"
+)
+
+func TestOrgRepoConfigHasPattern(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config *orgRepoConfig
+		org    string
+		repo   string
+		has    bool
+	}{
+		{
+			name:   "repo matches pattern",
+			config: newOrgRepoConfig(nil, sets.NewString("kubernetes-sigs/cluster-api-provider-*")),
+			org:    "kubernetes-sigs",
+			repo:   "kubernetes-sigs/cluster-api-provider-aws",
+			has:    true,
+		},
+		{
+			name:   "repo does not match pattern",
+			config: newOrgRepoConfig(nil, sets.NewString("kubernetes-sigs/cluster-api-provider-*")),
+			org:    "kubernetes-sigs",
+			repo:   "kubernetes-sigs/kind",
+			has:    false,
+		},
+		{
+			name:   "literal repo still matches",
+			config: newOrgRepoConfig(nil, sets.NewString("kubernetes/kubernetes")),
+			org:    "kubernetes",
+			repo:   "kubernetes/kubernetes",
+			has:    true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.config.has(tc.org, tc.repo); got != tc.has {
+				t.Errorf("has(%q, %q) = %v, want %v", tc.org, tc.repo, got, tc.has)
+			}
+		})
+	}
+}
+
+// TestOrgRepoConfigDifferenceExceptionSemantics covers the case the
+// unmatched-tide-query work needed: a repo pattern on one side of a
+// difference/intersection and an explicit org exception for the same repo
+// on the other side.
+func TestOrgRepoConfigDifferenceExceptionSemantics(t *testing.T) {
+	// c1 is "all of kubernetes-sigs except cluster-api-provider-aws",
+	// expressed as a literal org exception.
+	c1 := newOrgRepoConfig(map[string]sets.String{
+		"kubernetes-sigs": sets.NewString("kubernetes-sigs/cluster-api-provider-aws"),
+	}, sets.NewString())
+	// c2 covers cluster-api-provider-* repos via a pattern.
+	c2 := newOrgRepoConfig(nil, sets.NewString("kubernetes-sigs/cluster-api-provider-*"))
+
+	got := c1.difference(c2)
+	if got.repos.Has("kubernetes-sigs/cluster-api-provider-aws") {
+		t.Errorf("difference() kept kubernetes-sigs/cluster-api-provider-aws, which c2's pattern already covers and c1 excepts")
+	}
+	if got.repos.Has("kubernetes-sigs/cluster-api-provider-gcp") {
+		t.Errorf("difference() kept kubernetes-sigs/cluster-api-provider-gcp, which c2's pattern covers")
+	}
+}
+
+// TestOrgRepoConfigDifferenceSurvivesUnmatchedPattern covers the gap this
+// validation exists to catch: a pattern item on the left-hand side of a
+// difference that the right-hand side has nothing to say about must still
+// show up in the result's items(), not be silently dropped.
+func TestOrgRepoConfigDifferenceSurvivesUnmatchedPattern(t *testing.T) {
+	c1 := newOrgRepoConfig(nil, sets.NewString("kubernetes-sigs/cluster-api-provider-*"))
+	c2 := newOrgRepoConfig(nil, sets.NewString("kubernetes/kubernetes"))
+
+	got := c1.difference(c2)
+	found := false
+	for _, item := range got.items() {
+		if item.pattern == "kubernetes-sigs/cluster-api-provider-*" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("difference() dropped left-hand pattern kubernetes-sigs/cluster-api-provider-* that c2 has no match for; items() = %v", got.items())
+	}
+}
+
+// TestOrgRepoConfigDifferenceOrgExceptionAgainstPattern covers a whole-org
+// entry (no literal repos) on the receiver whose only overlap with c2 is a
+// repo pattern, not a literal repo: the pattern must still narrow the
+// resulting org-exceptions entry, the same way a literal repo in c2 would.
+func TestOrgRepoConfigDifferenceOrgExceptionAgainstPattern(t *testing.T) {
+	c1 := newOrgRepoConfig(map[string]sets.String{"kubernetes-sigs": sets.NewString()}, sets.NewString())
+	c2 := newOrgRepoConfig(nil, sets.NewString("kubernetes-sigs/cluster-api-provider-*"))
+
+	got := c1.difference(c2)
+	if got.has("kubernetes-sigs", "kubernetes-sigs/cluster-api-provider-aws") {
+		t.Errorf("difference() still has kubernetes-sigs/cluster-api-provider-aws, which c2's pattern covers and should have excepted from the whole-org entry")
+	}
+	if !got.has("kubernetes-sigs", "kubernetes-sigs/kind") {
+		t.Errorf("difference() dropped kubernetes-sigs/kind, which c2's pattern does not cover and should remain in the whole-org entry")
+	}
+}
+
+// TestOrgRepoConfigIntersectionOrgExceptionAgainstPattern is the
+// intersection counterpart: a whole-org entry on the receiver intersected
+// with a pattern-only c2 should include the repos the pattern covers.
+func TestOrgRepoConfigIntersectionOrgExceptionAgainstPattern(t *testing.T) {
+	c1 := newOrgRepoConfig(map[string]sets.String{"kubernetes-sigs": sets.NewString()}, sets.NewString())
+	c2 := newOrgRepoConfig(nil, sets.NewString("kubernetes-sigs/cluster-api-provider-*"))
+
+	got := c1.intersection(c2)
+	if !got.has("kubernetes-sigs", "kubernetes-sigs/cluster-api-provider-aws") {
+		t.Errorf("intersection() is missing kubernetes-sigs/cluster-api-provider-aws, which both the whole-org entry and c2's pattern cover")
+	}
+}
+
+func TestOrgRepoConfigIntersectionPatternAgainstLiteralRepo(t *testing.T) {
+	// c1 covers cluster-api-provider-* repos via a pattern.
+	c1 := newOrgRepoConfig(nil, sets.NewString("kubernetes-sigs/cluster-api-provider-*"))
+	// c2 is a single literal repo matching the pattern.
+	c2 := newOrgRepoConfig(nil, sets.NewString("kubernetes-sigs/cluster-api-provider-aws"))
+
+	got := c1.intersection(c2)
+	if !got.repos.Has("kubernetes-sigs/cluster-api-provider-aws") {
+		t.Errorf("intersection() dropped kubernetes-sigs/cluster-api-provider-aws, which c1's pattern covers and c2 names literally")
+	}
+}
+
+// TestCompileAllowedPluginsPolicyRegexScopeKey covers a scope key that is
+// neither a literal org/repo nor an "org/*" shorthand -- it must be
+// compiled as a regex and actually match the repos it's meant to cover,
+// rather than being silently inserted as a non-matching literal "repo".
+func TestCompileAllowedPluginsPolicyRegexScopeKey(t *testing.T) {
+	scopes, err := compileAllowedPluginsPolicy(map[string][]string{
+		"kubernetes-sigs/.+": {"^(lgtm|approve|release-note)$"},
+	})
+	if err != nil {
+		t.Fatalf("compileAllowedPluginsPolicy() returned error: %v", err)
+	}
+	if len(scopes) != 1 {
+		t.Fatalf("compileAllowedPluginsPolicy() returned %d scopes, want 1", len(scopes))
+	}
+	if scopes[0].scopePattern == nil {
+		t.Fatalf("scope for regex key %q has nil scopePattern; key was silently treated as a literal repo", scopes[0].name)
+	}
+	owning := scopesOwning(scopes, "kubernetes-sigs", "kubernetes-sigs/cluster-api-provider-aws")
+	if len(owning) != 1 {
+		t.Errorf("scopesOwning() = %d scopes, want 1 to own kubernetes-sigs/cluster-api-provider-aws", len(owning))
+	}
+}
+
+// TestIsStrictPrecedence covers the precedence enforcement-config scopes
+// must take over the global --strict default: a matching error scope always
+// wins, a matching warn scope always wins over global strict, and the
+// global --strict default only applies when no scope matches at all.
+func TestIsStrictPrecedence(t *testing.T) {
+	policies := []EnforcementPolicy{
+		{
+			Warning: mismatchedTideWarning,
+			Action:  errorAction,
+			Scopes:  []EnforcementScope{{Org: "kubernetes", Repos: []string{"kubernetes/kubernetes"}}},
+		},
+		{
+			Warning: mismatchedTideWarning,
+			Action:  warnAction,
+			Scopes:  []EnforcementScope{{Org: "kubernetes", Repos: []string{"kubernetes/test-infra"}}},
+		},
+	}
+	table, err := newEnforcementTable(true, policies)
+	if err != nil {
+		t.Fatalf("newEnforcementTable() returned error: %v", err)
+	}
+
+	testCases := []struct {
+		name    string
+		warning string
+		org     string
+		repo    string
+		strict  bool
+	}{
+		{
+			name:    "error scope wins over global strict",
+			warning: mismatchedTideWarning,
+			org:     "kubernetes",
+			repo:    "kubernetes/kubernetes",
+			strict:  true,
+		},
+		{
+			name:    "warn scope wins over global strict",
+			warning: mismatchedTideWarning,
+			org:     "kubernetes",
+			repo:    "kubernetes/test-infra",
+			strict:  false,
+		},
+		{
+			name:    "falls back to global strict when no scope matches",
+			warning: mismatchedTideWarning,
+			org:     "kubernetes",
+			repo:    "kubernetes/community",
+			strict:  true,
+		},
+		{
+			name:    "falls back to global strict for a different warning entirely",
+			warning: nonDecoratedJobsWarning,
+			org:     "kubernetes",
+			repo:    "kubernetes/kubernetes",
+			strict:  true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := table.isStrict(tc.warning, tc.org, tc.repo); got != tc.strict {
+				t.Errorf("isStrict(%q, %q, %q) = %v, want %v", tc.warning, tc.org, tc.repo, got, tc.strict)
+			}
+		})
+	}
+}
+
+// TestNewEnforcementTableMergesSharedWarningAction covers two policies that
+// lay out the same warning+action in separate entries (e.g. one per team):
+// both scopes must apply, not just the last one processed.
+func TestNewEnforcementTableMergesSharedWarningAction(t *testing.T) {
+	policies := []EnforcementPolicy{
+		{
+			Warning: mismatchedTideWarning,
+			Action:  errorAction,
+			Scopes:  []EnforcementScope{{Org: "kubernetes", Repos: []string{"kubernetes/kubernetes"}}},
+		},
+		{
+			Warning: mismatchedTideWarning,
+			Action:  errorAction,
+			Scopes:  []EnforcementScope{{Org: "envoyproxy", Repos: []string{"envoyproxy/envoy"}}},
+		},
+	}
+	table, err := newEnforcementTable(false, policies)
+	if err != nil {
+		t.Fatalf("newEnforcementTable() returned error: %v", err)
+	}
+	if !table.isStrict(mismatchedTideWarning, "kubernetes", "kubernetes/kubernetes") {
+		t.Errorf("isStrict() = false for kubernetes/kubernetes, want true: the first error policy's scope vanished when a second one for the same warning+action was added")
+	}
+	if !table.isStrict(mismatchedTideWarning, "envoyproxy", "envoyproxy/envoy") {
+		t.Errorf("isStrict() = false for envoyproxy/envoy, want true")
+	}
+}
+
+func TestIsStrictFalseGlobalDefault(t *testing.T) {
+	table, err := newEnforcementTable(false, []EnforcementPolicy{
+		{
+			Warning: mismatchedTideWarning,
+			Action:  errorAction,
+			Scopes:  []EnforcementScope{{Org: "kubernetes", Repos: []string{"kubernetes/kubernetes"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("newEnforcementTable() returned error: %v", err)
+	}
+	if !table.isStrict(mismatchedTideWarning, "kubernetes", "kubernetes/kubernetes") {
+		t.Errorf("isStrict() = false for a repo matching an error scope, want true even though global strict is false")
+	}
+	if table.isStrict(mismatchedTideWarning, "kubernetes", "kubernetes/community") {
+		t.Errorf("isStrict() = true for a repo matching no scope, want false since global strict is false")
+	}
+}
+
+func TestCompileAllowedPluginsPolicyInvalidRegex(t *testing.T) {
+	if _, err := compileAllowedPluginsPolicy(map[string][]string{
+		"kubernetes/*": {"("},
+	}); err == nil {
+		t.Errorf("compileAllowedPluginsPolicy() returned no error for an invalid plugin regex")
+	}
+}
+
+func TestValidateAllowedPlugins(t *testing.T) {
+	scopes, err := compileAllowedPluginsPolicy(map[string][]string{
+		"kubernetes/*": {"^(lgtm|approve)$"},
+	})
+	if err != nil {
+		t.Fatalf("compileAllowedPluginsPolicy() returned error: %v", err)
+	}
+	pcfg := &plugins.Configuration{
+		Plugins: map[string][]string{
+			"kubernetes/kubernetes": {"lgtm", "trigger"},
+		},
+	}
+
+	findings := validateAllowedPlugins(pcfg, scopes)
+	if len(findings) != 1 {
+		t.Fatalf("validateAllowedPlugins() = %d findings, want 1", len(findings))
+	}
+	if findings[0].Warning != disallowedPluginsWarning {
+		t.Errorf("findings[0].Warning = %q, want %q", findings[0].Warning, disallowedPluginsWarning)
+	}
+	if findings[0].Repo != "kubernetes/kubernetes" {
+		t.Errorf("findings[0].Repo = %q, want %q", findings[0].Repo, "kubernetes/kubernetes")
+	}
+}
+
+// TestCompileAllowedPluginsPolicyBareOrgKey covers a bare org key (e.g.
+// "kubernetes", as opposed to "kubernetes/*") which the pluginAllowScope
+// doc comment promises is matched the same way as the "/*" form.
+func TestCompileAllowedPluginsPolicyBareOrgKey(t *testing.T) {
+	scopes, err := compileAllowedPluginsPolicy(map[string][]string{
+		"kubernetes": {"^(lgtm|approve)$"},
+	})
+	if err != nil {
+		t.Fatalf("compileAllowedPluginsPolicy() returned error: %v", err)
+	}
+	owning := scopesOwning(scopes, "kubernetes", "kubernetes/kubernetes")
+	if len(owning) != 1 {
+		t.Fatalf("scopesOwning() = %d scopes, want 1: a bare org key must own every repo in that org", len(owning))
+	}
+	if !pluginAllowedByScopes(owning, "lgtm") {
+		t.Errorf("pluginAllowedByScopes() = false for lgtm under a bare org key, want true")
+	}
+}
+
+func TestReposMatchingPatterns(t *testing.T) {
+	_, _, ok := compileRepoPattern("kubernetes/kubernetes")
+	if ok {
+		t.Fatalf("compileRepoPattern treated a literal repo as a pattern")
+	}
+	re, raw, ok := compileRepoPattern("kubernetes-sigs/cluster-api-provider-*")
+	if !ok {
+		t.Fatalf("compileRepoPattern did not treat a glob repo as a pattern")
+	}
+	if raw != "kubernetes-sigs/cluster-api-provider-*" {
+		t.Errorf("compileRepoPattern raw = %q, want original pattern text", raw)
+	}
+
+	repos := sets.NewString(
+		"kubernetes-sigs/cluster-api-provider-aws",
+		"kubernetes-sigs/cluster-api-provider-gcp",
+		"kubernetes-sigs/kind",
+	)
+	want := sets.NewString(
+		"kubernetes-sigs/cluster-api-provider-aws",
+		"kubernetes-sigs/cluster-api-provider-gcp",
+	)
+	if got := reposMatchingPatterns(repos, []*regexp.Regexp{re}); !reflect.DeepEqual(got.List(), want.List()) {
+		t.Errorf("reposMatchingPatterns() = %v, want %v", got.List(), want.List())
+	}
+}